@@ -19,19 +19,27 @@ const (
 
 // BM25S implements a modified BM25 algorithm for short texts
 type BM25S struct {
-	docs          []string              // Document collection
-	avgDocLength  float64               // Average document length (in terms)
-	k1            float64               // Term frequency saturation parameter (1.2-2.0)
-	b             float64               // Document length normalization parameter (0.3 for short texts)
-	tokenizer     func(string) []string // Tokenization and stemming function
-	termDocFreq   map[string]int        // DF: number of documents containing the term
-	termTotalFreq map[string]int        // Total frequency of the term across all documents
-	docTermFreqs  []map[string]int      // TF for each document
-	docLengths    []int                 // Document lengths (in terms)
-	totalTerms    int                   // Total number of terms across the collection
-	autok1        bool
-	autob         bool
-	useIWF        bool // Use Inverse Word Frequency instead of IDF
+	docs             []string              // Document collection
+	avgDocLength     float64               // Average document length (in terms)
+	k1               float64               // Term frequency saturation parameter (1.2-2.0)
+	b                float64               // Document length normalization parameter (0.3 for short texts)
+	tokenizer        func(string) []string // Tokenization and stemming function
+	termDocFreq      map[string]int        // DF: number of documents containing the term
+	termTotalFreq    map[string]int        // Total frequency of the term across all documents
+	docTermFreqs     []map[string]int      // TF for each document
+	docLengths       []int                 // Document lengths (in terms)
+	totalTerms       int                   // Total number of terms across the collection
+	autok1           bool
+	autob            bool
+	useIWF           bool                 // Use Inverse Word Frequency instead of IDF
+	language         string               // Declared corpus language, informational (stemming remains auto-detected per word)
+	tombstoned       []bool               // Marks docIndex slots removed by RemoveDocument; reclaimed by Compact
+	activeDocs       int                  // Number of non-tombstoned documents, used for avgDocLength
+	docLanguages     []string             // Per-document stemmer language override, parallel to docs; "" means auto-detect
+	docTermPositions []map[string][]int   // Term -> ordered token positions per document, used for phrase queries
+	postings         map[string][]posting // Inverted term -> (docIndex, tf) index, rebuilt lazily by ensurePostings
+	postingsDirty    bool                 // Set after any index mutation to force a postings rebuild
+	workers          int                  // Shard count for Search's postings accumulation; <= 1 is sequential
 }
 
 // Option allows configuring BM25S parameters
@@ -67,10 +75,26 @@ func WithTokenizer(f func(string) []string) Option {
 	}
 }
 
+// WithLanguage records the corpus language for persistence and downstream
+// tooling. It is informational only: stemming still auto-detects script per
+// word (see stemWord).
+func WithLanguage(language string) Option {
+	return func(b *BM25S) {
+		b.language = language
+	}
+}
+
 // New creates and initializes a new BM25S instance
 func New(docs []string, opts ...Option) *BM25S {
+	return newBM25S(docs, nil, opts...)
+}
+
+// newBM25S is the shared constructor behind New and NewWithDocuments.
+// languages may be nil (auto-detect every document) or parallel to docs.
+func newBM25S(docs []string, languages []string, opts ...Option) *BM25S {
 	b := &BM25S{
 		docs:          docs,
+		docLanguages:  languages,
 		k1:            ShortK1,
 		b:             ShortB,
 		autok1:        true,
@@ -120,7 +144,8 @@ func (b *BM25S) tokenizeAndStem(text string) []string {
 	return terms
 }
 
-// stemWord applies language-specific stemming
+// stemWord applies script-based auto-detected stemming (see stemWordLang for
+// per-document explicit language selection)
 func (b *BM25S) stemWord(word string) string {
 	// Count Cyrillic and Latin characters
 	var cyrCount, latCount, digitCount int
@@ -159,30 +184,37 @@ func (b *BM25S) stemWord(word string) string {
 // buildIndex constructs the index for the document collection
 func (b *BM25S) buildIndex() {
 	b.docTermFreqs = make([]map[string]int, len(b.docs))
+	b.docTermPositions = make([]map[string][]int, len(b.docs))
 	b.docLengths = make([]int, len(b.docs))
+	b.tombstoned = make([]bool, len(b.docs))
 	totalLength := 0
 
 	for i, doc := range b.docs {
-		terms := b.tokenizer(doc)
+		terms := b.tokenizeDoc(i, doc)
 		b.docLengths[i] = len(terms)
 		totalLength += len(terms)
 
 		tf := make(map[string]int)
-		for _, term := range terms {
+		positions := make(map[string][]int)
+		for j, term := range terms {
 			tf[term]++
+			positions[term] = append(positions[term], j)
 			b.termTotalFreq[term]++
 			b.totalTerms++
 		}
 		b.docTermFreqs[i] = tf
+		b.docTermPositions[i] = positions
 
 		for term := range tf {
 			b.termDocFreq[term]++
 		}
 	}
 
-	if len(b.docs) > 0 {
-		b.avgDocLength = float64(totalLength) / float64(len(b.docs))
+	b.activeDocs = len(b.docs)
+	if b.activeDocs > 0 {
+		b.avgDocLength = float64(totalLength) / float64(b.activeDocs)
 	}
+	b.postingsDirty = true
 }
 
 // safeIDF calculates a stable Inverse Document Frequency
@@ -212,31 +244,18 @@ func (b *BM25S) termWeight(term string) float64 {
 // Score calculates the relevance score of a document to the query
 // Automatically adjusts calculation for long documents
 func (b *BM25S) Score(docIndex int, query string) float64 {
-	queryTerms := b.tokenizer(query)
+	return b.scoreTerms(docIndex, b.tokenizer(query))
+}
+
+// scoreTerms is the BM25S scoring core shared by Score and SearchQuery,
+// operating on already-tokenized query terms.
+func (b *BM25S) scoreTerms(docIndex int, queryTerms []string) float64 {
 	docTF := b.docTermFreqs[docIndex]
-	docLength := float64(b.docLengths[docIndex])
 	score := 0.0
 
-	// Determine if this is a long document
-	isLongDoc := docLength > 2*b.avgDocLength
-
 	for _, term := range queryTerms {
 		if tf, ok := docTF[term]; ok && tf > 0 {
-			weight := b.termWeight(term)
-			tf := float64(tf)
-
-			numerator := tf * (b.k1 + 1)
-			denominator := tf + b.k1*(1-b.b+b.b*(docLength/b.avgDocLength))
-
-			// Different calculation for long documents
-			if isLongDoc {
-				// Additional penalty for very long documents
-				lengthPenalty := math.Min(1.0, b.avgDocLength/docLength)
-				score += weight * numerator / denominator * lengthPenalty
-			} else {
-				// Standard BM25S calculation for short/medium documents
-				score += weight * numerator / denominator
-			}
+			score += b.termContribution(docIndex, term, tf)
 		}
 	}
 
@@ -250,27 +269,28 @@ type SearchResult struct {
 	Doc      string  // Document text
 }
 
-// Search performs a search and returns top-N results
+// Search performs a search and returns top-N results. Beyond plain
+// bag-of-words it recognizes `"quoted phrases"` (with optional `~N` slop),
+// `+required`/`-excluded` terms, and `field:term` prefixes; free text with
+// none of these is scored exactly as before.
 func (b *BM25S) Search(query string, topN int) []SearchResult {
-	results := make([]SearchResult, 0, len(b.docs))
-	for i := range b.docs {
-		score := b.Score(i, query)
-		if score > 0 {
-			results = append(results, SearchResult{
-				DocIndex: i,
-				Score:    score,
-				Doc:      b.docs[i],
-			})
-		}
+	ast, hasOperators := b.parseQuery(query)
+	if hasOperators {
+		return b.searchAST(ast, topN)
 	}
 
+	return b.searchPostings(query, topN)
+}
+
+// sortResultsByScore sorts results by descending score, shared by Search,
+// SearchQuery, and the phrase/boolean query path. Ties break on ascending
+// DocIndex so results are reproducible regardless of the map iteration
+// order that produced the candidate set.
+func sortResultsByScore(results []SearchResult) {
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].DocIndex < results[j].DocIndex
 	})
-
-	if topN > 0 && len(results) > topN {
-		results = results[:topN]
-	}
-
-	return results
 }
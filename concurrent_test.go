@@ -0,0 +1,95 @@
+package bm25s
+
+import "testing"
+
+// TestSearchPostingsMatchesSequential verifies that the postings-based
+// Search path produces the same ranking as scoring every document
+// individually via Score.
+func TestSearchPostingsMatchesSequential(t *testing.T) {
+	docs := []string{
+		"The quick brown fox jumps over the lazy dog",
+		"A fox fled from danger",
+		"Completely unrelated text about gardening",
+	}
+	bm25 := New(docs)
+
+	results := bm25.Search("fox", 3)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results for 'fox', got %d", len(results))
+	}
+	if results[0].DocIndex != 1 || results[1].DocIndex != 0 {
+		t.Errorf("Expected doc indices [1, 0], got [%d, %d]", results[0].DocIndex, results[1].DocIndex)
+	}
+
+	// Score() should agree with the ranking independently.
+	if bm25.Score(1, "fox") <= bm25.Score(0, "fox") {
+		t.Error("Expected Score(1, 'fox') > Score(0, 'fox') to match Search ranking")
+	}
+}
+
+// TestSearchWithWorkers verifies that sharding the query across workers
+// produces the same results as the sequential path.
+func TestSearchWithWorkers(t *testing.T) {
+	docs := []string{
+		"alpha beta gamma delta",
+		"beta gamma epsilon",
+		"gamma delta zeta",
+	}
+
+	sequential := New(docs).Search("beta gamma delta", 3)
+	sharded := New(docs, WithWorkers(4)).Search("beta gamma delta", 3)
+
+	if len(sequential) != len(sharded) {
+		t.Fatalf("Expected equal result counts, got %d vs %d", len(sequential), len(sharded))
+	}
+	for i := range sequential {
+		if sequential[i].DocIndex != sharded[i].DocIndex {
+			t.Errorf("Result %d: expected DocIndex %d, got %d", i, sequential[i].DocIndex, sharded[i].DocIndex)
+		}
+		if sequential[i].Score != sharded[i].Score {
+			t.Errorf("Result %d: expected Score %f, got %f", i, sequential[i].Score, sharded[i].Score)
+		}
+	}
+}
+
+// TestSearchTieBreakIsDeterministic verifies that documents tied on score
+// sort in a stable, reproducible order (ascending DocIndex) instead of
+// varying with postings map iteration order across repeated calls.
+func TestSearchTieBreakIsDeterministic(t *testing.T) {
+	docs := []string{
+		"alpha beta gamma delta",
+		"beta gamma epsilon",
+		"gamma delta zeta",
+	}
+	bm25 := New(docs)
+
+	for i := 0; i < 20; i++ {
+		results := bm25.Search("beta gamma delta", 3)
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 results, got %d", len(results))
+		}
+		if results[1].DocIndex != 1 || results[2].DocIndex != 2 {
+			t.Fatalf("Expected tied docs 1 and 2 in stable DocIndex order, got %v", results)
+		}
+	}
+}
+
+// TestSearchTopNHeap verifies that a small topN still returns the highest
+// scoring results when the candidate set is larger.
+func TestSearchTopNHeap(t *testing.T) {
+	docs := []string{
+		"fox fox fox fox",
+		"fox fox",
+		"fox",
+		"no match here",
+	}
+	bm25 := New(docs)
+
+	results := bm25.Search("fox", 2)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].DocIndex != 0 || results[1].DocIndex != 1 {
+		t.Errorf("Expected top 2 by score to be [0, 1], got [%d, %d]", results[0].DocIndex, results[1].DocIndex)
+	}
+}
@@ -0,0 +1,180 @@
+package bm25s
+
+import "fmt"
+
+// AddDocument tokenizes and indexes a single document with the configured
+// tokenizer (script-based auto-detected stemming by default), appending it
+// to the collection. It returns the assigned docIndex, which is stable
+// across subsequent AddDocument/RemoveDocument calls until Compact is run.
+//
+// For a corpus built with NewWithDocuments, AddDocument records "" (auto-
+// detect) as the document's language; use AddDocumentWithLanguage to
+// declare one.
+func (b *BM25S) AddDocument(doc string) int {
+	return b.AddDocumentWithLanguage(doc, "")
+}
+
+// AddDocumentWithLanguage is AddDocument with an explicit declared language
+// override (see Document.Language), stemmed the same way tokenizeDoc would
+// for a document added via NewWithDocuments. Use "" for auto-detection.
+func (b *BM25S) AddDocumentWithLanguage(doc, language string) int {
+	docIndex := len(b.docs)
+
+	var terms []string
+	if language != "" {
+		terms = b.tokenizeAndStemLang(doc, language)
+	} else {
+		terms = b.tokenizer(doc)
+	}
+
+	tf := make(map[string]int, len(terms))
+	positions := make(map[string][]int)
+	for j, term := range terms {
+		tf[term]++
+		positions[term] = append(positions[term], j)
+		b.termTotalFreq[term]++
+		b.totalTerms++
+	}
+	for term := range tf {
+		b.termDocFreq[term]++
+	}
+
+	b.docs = append(b.docs, doc)
+	b.docTermFreqs = append(b.docTermFreqs, tf)
+	b.docTermPositions = append(b.docTermPositions, positions)
+	b.docLengths = append(b.docLengths, len(terms))
+	b.tombstoned = append(b.tombstoned, false)
+	b.activeDocs++
+	if b.docLanguages != nil {
+		b.docLanguages = append(b.docLanguages, language)
+	}
+
+	b.recomputeAvgDocLength()
+	b.applyLengthHeuristic()
+	b.postingsDirty = true
+
+	return docIndex
+}
+
+// AddDocuments indexes multiple documents and returns their assigned
+// docIndex values in the same order as docs.
+func (b *BM25S) AddDocuments(docs []string) []int {
+	indices := make([]int, len(docs))
+	for i, doc := range docs {
+		indices[i] = b.AddDocument(doc)
+	}
+	return indices
+}
+
+// RemoveDocument tombstones the document at docIndex, removing its
+// contribution to the collection statistics while keeping docIndex stable
+// for any SearchResult obtained before the call. Call Compact to reclaim
+// the tombstoned slots.
+func (b *BM25S) RemoveDocument(docIndex int) error {
+	if docIndex < 0 || docIndex >= len(b.docs) {
+		return fmt.Errorf("docIndex %d out of range [0, %d)", docIndex, len(b.docs))
+	}
+	if b.tombstoned[docIndex] {
+		return fmt.Errorf("docIndex %d is already removed", docIndex)
+	}
+
+	for term, tf := range b.docTermFreqs[docIndex] {
+		b.termTotalFreq[term] -= tf
+		b.totalTerms -= tf
+		if b.termDocFreq[term]--; b.termDocFreq[term] <= 0 {
+			delete(b.termDocFreq, term)
+			delete(b.termTotalFreq, term)
+		}
+	}
+
+	b.tombstoned[docIndex] = true
+	b.activeDocs--
+
+	b.recomputeAvgDocLength()
+	b.applyLengthHeuristic()
+	b.postingsDirty = true
+
+	return nil
+}
+
+// Compact physically removes tombstoned documents, remapping docIndex
+// values to a dense [0, activeDocs) range. It returns a mapping from old
+// docIndex to new docIndex for every document that survived compaction, so
+// callers with parallel arrays (e.g. rag.DocMatch.documents) can stay in
+// sync.
+func (b *BM25S) Compact() map[int]int {
+	remap := make(map[int]int, b.activeDocs)
+
+	docs := make([]string, 0, b.activeDocs)
+	docTermFreqs := make([]map[string]int, 0, b.activeDocs)
+	docTermPositions := make([]map[string][]int, 0, b.activeDocs)
+	docLengths := make([]int, 0, b.activeDocs)
+	var docLanguages []string
+	if b.docLanguages != nil {
+		docLanguages = make([]string, 0, b.activeDocs)
+	}
+
+	for old := range b.docs {
+		if b.tombstoned[old] {
+			continue
+		}
+		remap[old] = len(docs)
+		docs = append(docs, b.docs[old])
+		docTermFreqs = append(docTermFreqs, b.docTermFreqs[old])
+		docTermPositions = append(docTermPositions, b.docTermPositions[old])
+		docLengths = append(docLengths, b.docLengths[old])
+		if docLanguages != nil {
+			docLanguages = append(docLanguages, b.docLanguages[old])
+		}
+	}
+
+	b.docs = docs
+	b.docTermFreqs = docTermFreqs
+	b.docTermPositions = docTermPositions
+	b.docLanguages = docLanguages
+	b.docLengths = docLengths
+	b.tombstoned = make([]bool, len(docs))
+	b.activeDocs = len(docs)
+	b.postingsDirty = true
+
+	return remap
+}
+
+// recomputeAvgDocLength recalculates avgDocLength over the non-tombstoned
+// documents after an AddDocument/RemoveDocument call.
+func (b *BM25S) recomputeAvgDocLength() {
+	if b.activeDocs == 0 {
+		b.avgDocLength = 0
+		return
+	}
+
+	totalLength := 0
+	for i, length := range b.docLengths {
+		if b.tombstoned[i] {
+			continue
+		}
+		totalLength += length
+	}
+	b.avgDocLength = float64(totalLength) / float64(b.activeDocs)
+}
+
+// applyLengthHeuristic re-applies the auto-tuned k1/b long-document
+// heuristic from New whenever avgDocLength drifts across the threshold.
+func (b *BM25S) applyLengthHeuristic() {
+	if b.avgDocLength > 100.0 {
+		if b.autok1 {
+			b.k1 = LongK1
+		}
+		if b.autob {
+			b.b = LongB
+		}
+		return
+	}
+
+	if b.autok1 {
+		b.k1 = ShortK1
+	}
+	if b.autob {
+		b.b = ShortB
+	}
+}
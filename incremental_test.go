@@ -0,0 +1,113 @@
+package bm25s
+
+import "testing"
+
+// TestAddDocument verifies that newly added documents are searchable and
+// that their docIndex is assigned in append order.
+func TestAddDocument(t *testing.T) {
+	bm25 := New([]string{"The quick brown fox"})
+
+	idx := bm25.AddDocument("A fox fled from danger")
+	if idx != 1 {
+		t.Fatalf("Expected assigned docIndex 1, got %d", idx)
+	}
+
+	results := bm25.Search("fox", 2)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results after AddDocument, got %d", len(results))
+	}
+}
+
+// TestAddDocuments verifies that docIndex values are returned in order.
+func TestAddDocuments(t *testing.T) {
+	bm25 := New([]string{"first document"})
+
+	indices := bm25.AddDocuments([]string{"second document", "third document"})
+	if len(indices) != 2 || indices[0] != 1 || indices[1] != 2 {
+		t.Errorf("Expected indices [1, 2], got %v", indices)
+	}
+}
+
+// TestRemoveDocument verifies tombstoning removes a document from search
+// results while keeping other docIndex values stable.
+func TestRemoveDocument(t *testing.T) {
+	bm25 := New([]string{
+		"The quick brown fox",
+		"A fox fled from danger",
+		"Irrelevant document",
+	})
+
+	if err := bm25.RemoveDocument(1); err != nil {
+		t.Fatalf("RemoveDocument failed: %v", err)
+	}
+
+	results := bm25.Search("fox", 5)
+	for _, r := range results {
+		if r.DocIndex == 1 {
+			t.Errorf("Expected tombstoned docIndex 1 to be excluded from results")
+		}
+	}
+
+	if err := bm25.RemoveDocument(1); err == nil {
+		t.Error("Expected error when removing an already-removed docIndex")
+	}
+
+	if err := bm25.RemoveDocument(99); err == nil {
+		t.Error("Expected error when removing an out-of-range docIndex")
+	}
+}
+
+// TestAddDocumentWithLanguageKeepsCompactInSync verifies that a document
+// added via AddDocumentWithLanguage to a NewWithDocuments corpus gets the
+// next docIndex and keeps its declared language in docLanguages, which
+// stays parallel to docs through RemoveDocument/Compact.
+func TestAddDocumentWithLanguageKeepsCompactInSync(t *testing.T) {
+	bm25 := NewWithDocuments([]Document{
+		{Content: "Быстрая лисица убегала", Language: "ru"},
+		{Content: "The quick foxes were running", Language: "en"},
+	})
+
+	idx := bm25.AddDocumentWithLanguage("Une lisiere rapide et gracieuse", "fr")
+	if idx != 2 {
+		t.Fatalf("Expected assigned docIndex 2, got %d", idx)
+	}
+
+	if err := bm25.RemoveDocument(0); err != nil {
+		t.Fatalf("RemoveDocument failed: %v", err)
+	}
+
+	remap := bm25.Compact()
+	if len(bm25.docs) != 2 {
+		t.Fatalf("Expected 2 documents after Compact, got %d", len(bm25.docs))
+	}
+	if len(bm25.docLanguages) != len(bm25.docs) {
+		t.Fatalf("Expected docLanguages to stay parallel to docs, got %d vs %d", len(bm25.docLanguages), len(bm25.docs))
+	}
+	if newIdx, ok := remap[2]; !ok || bm25.docLanguages[newIdx] != "fr" {
+		t.Errorf("Expected the added French document to survive Compact with its language intact, got %v", bm25.docLanguages)
+	}
+}
+
+// TestCompact verifies that Compact reclaims tombstoned slots and returns
+// the old-to-new docIndex mapping for survivors.
+func TestCompact(t *testing.T) {
+	bm25 := New([]string{"doc zero", "doc one", "doc two"})
+
+	if err := bm25.RemoveDocument(1); err != nil {
+		t.Fatalf("RemoveDocument failed: %v", err)
+	}
+
+	remap := bm25.Compact()
+	if len(bm25.docs) != 2 {
+		t.Fatalf("Expected 2 documents after Compact, got %d", len(bm25.docs))
+	}
+	if newIdx, ok := remap[0]; !ok || newIdx != 0 {
+		t.Errorf("Expected old index 0 to remap to 0, got %d (ok=%v)", newIdx, ok)
+	}
+	if newIdx, ok := remap[2]; !ok || newIdx != 1 {
+		t.Errorf("Expected old index 2 to remap to 1, got %d (ok=%v)", newIdx, ok)
+	}
+	if _, ok := remap[1]; ok {
+		t.Error("Expected tombstoned old index 1 to be absent from the remap")
+	}
+}
@@ -0,0 +1,134 @@
+package bm25s
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kljensen/snowball/english"
+	"github.com/kljensen/snowball/french"
+	"github.com/kljensen/snowball/russian"
+	"github.com/kljensen/snowball/spanish"
+)
+
+// Document pairs document content with an explicit stemmer language, for
+// corpora that mix languages document-by-document rather than relying on
+// per-word script detection.
+type Document struct {
+	Content  string
+	Language string // e.g. "en", "ru", "fr", "es", "none" for no stemming, or "" to auto-detect
+}
+
+// Query pairs query text with an explicit stemmer language, mirroring
+// Document for the search side.
+type Query struct {
+	Text     string
+	Language string // see Document.Language
+}
+
+// NewWithDocuments creates a BM25S instance where each document is stemmed
+// with its own declared language's stemmer instead of the single
+// script-based auto-detection New uses. Documents with an empty Language
+// fall back to auto-detection.
+func NewWithDocuments(docs []Document, opts ...Option) *BM25S {
+	contents := make([]string, len(docs))
+	languages := make([]string, len(docs))
+	for i, d := range docs {
+		contents[i] = d.Content
+		languages[i] = d.Language
+	}
+
+	return newBM25S(contents, languages, opts...)
+}
+
+// tokenizeDoc tokenizes docs[i], honoring its declared language override
+// when present; otherwise it falls back to the configured tokenizer (which
+// defaults to script-based auto-detection).
+func (b *BM25S) tokenizeDoc(i int, doc string) []string {
+	if i < len(b.docLanguages) && b.docLanguages[i] != "" {
+		return b.tokenizeAndStemLang(doc, b.docLanguages[i])
+	}
+	return b.tokenizer(doc)
+}
+
+// tokenizeAndStemLang tokenizes text and stems every term with the
+// stemmer for the given language, bypassing script auto-detection.
+func (b *BM25S) tokenizeAndStemLang(text, language string) []string {
+	words := strings.Fields(strings.ToLower(text))
+	terms := make([]string, 0, len(words))
+
+	for _, word := range words {
+		word = strings.TrimFunc(word, func(r rune) bool {
+			return strings.ContainsRune(".,!?;:\"'()[]{}", r)
+		})
+
+		if utf8.RuneCountInString(word) < 2 {
+			continue
+		}
+
+		terms = append(terms, b.stemWordLang(word, language))
+	}
+
+	return terms
+}
+
+// stemWordLang stems word with the stemmer for the declared language. An
+// unrecognized or empty language (including "de", for which this module has
+// no stemmer available) falls back to script-based auto-detection (see
+// stemWord); "none" disables stemming entirely.
+func (b *BM25S) stemWordLang(word, language string) string {
+	switch language {
+	case "en":
+		return english.Stem(word, false)
+	case "ru":
+		return russian.Stem(word, false)
+	case "fr":
+		return french.Stem(word, false)
+	case "es":
+		return spanish.Stem(word, false)
+	case "none":
+		return word
+	default:
+		return b.stemWord(word)
+	}
+}
+
+// SearchQuery performs a search using a language-aware Query, stemming
+// query terms with the declared language instead of auto-detection. With
+// an empty Language it is Search; otherwise the text is scored as free
+// text, and the phrase and boolean operators Search recognizes are not
+// parsed.
+func (b *BM25S) SearchQuery(q Query, topN int) []SearchResult {
+	if q.Language == "" {
+		return b.Search(q.Text, topN)
+	}
+
+	queryTerms := b.tokenizeAndStemLang(q.Text, q.Language)
+	return b.searchTerms(queryTerms, topN)
+}
+
+// searchTerms scores every active document against a pre-tokenized set of
+// query terms and returns the top-N results, mirroring Search's ranking.
+func (b *BM25S) searchTerms(queryTerms []string, topN int) []SearchResult {
+	results := make([]SearchResult, 0, len(b.docs))
+	for i := range b.docs {
+		if i < len(b.tombstoned) && b.tombstoned[i] {
+			continue
+		}
+		score := b.scoreTerms(i, queryTerms)
+		if score > 0 {
+			results = append(results, SearchResult{
+				DocIndex: i,
+				Score:    score,
+				Doc:      b.docs[i],
+			})
+		}
+	}
+
+	sortResultsByScore(results)
+
+	if topN > 0 && len(results) > topN {
+		results = results[:topN]
+	}
+
+	return results
+}
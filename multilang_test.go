@@ -0,0 +1,61 @@
+package bm25s
+
+import "testing"
+
+// TestNewWithDocumentsPerLanguageStemming verifies that each document is
+// stemmed with its declared language rather than a single auto-detected
+// stemmer for the whole corpus.
+func TestNewWithDocumentsPerLanguageStemming(t *testing.T) {
+	docs := []Document{
+		{Content: "Быстрая лисица убегала", Language: "ru"},
+		{Content: "The quick foxes were running", Language: "en"},
+	}
+	bm25 := NewWithDocuments(docs)
+
+	if df, ok := bm25.termDocFreq["лисиц"]; !ok || df != 1 {
+		t.Errorf("Expected Russian stemmer to produce 'лисиц', got termDocFreq=%v", bm25.termDocFreq)
+	}
+	if df, ok := bm25.termDocFreq["fox"]; !ok || df != 1 {
+		t.Errorf("Expected English stemmer to produce 'fox', got termDocFreq=%v", bm25.termDocFreq)
+	}
+}
+
+// TestNewWithDocumentsNoneLanguage verifies that "none" disables stemming.
+func TestNewWithDocumentsNoneLanguage(t *testing.T) {
+	docs := []Document{
+		{Content: "running runners", Language: "none"},
+	}
+	bm25 := NewWithDocuments(docs)
+
+	if _, ok := bm25.termDocFreq["running"]; !ok {
+		t.Error("Expected 'running' to remain unstemmed with Language \"none\"")
+	}
+}
+
+// TestNewWithDocumentsUnsupportedLanguageFallsBack verifies that a language
+// with no dedicated stemmer (e.g. "de") falls back to script-based
+// auto-detection instead of failing or skipping stemming.
+func TestNewWithDocumentsUnsupportedLanguageFallsBack(t *testing.T) {
+	docs := []Document{
+		{Content: "The quick foxes were running", Language: "de"},
+	}
+	bm25 := NewWithDocuments(docs)
+
+	if df, ok := bm25.termDocFreq["fox"]; !ok || df != 1 {
+		t.Errorf("Expected unsupported language \"de\" to fall back to auto-detected stemming, got termDocFreq=%v", bm25.termDocFreq)
+	}
+}
+
+// TestSearchQuery verifies that SearchQuery stems the query with the
+// declared language before matching.
+func TestSearchQuery(t *testing.T) {
+	docs := []Document{
+		{Content: "Быстрая лисица убегала от опасности", Language: "ru"},
+	}
+	bm25 := NewWithDocuments(docs)
+
+	results := bm25.SearchQuery(Query{Text: "лисицы", Language: "ru"}, 1)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result for stemmed Russian query, got %d", len(results))
+	}
+}
@@ -0,0 +1,376 @@
+package bm25s
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// byteReader is satisfied by *bufio.Reader; binary.ReadUvarint needs
+// ReadByte and io.ReadFull needs Read, so Decode wraps its input once and
+// threads the same reader through every field read below.
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// formatVersion identifies the on-disk layout written by Encode/Save.
+// Bump it whenever the packed postings layout or header fields change.
+//
+// v2 adds the per-document tombstone bitmap so a Decode of an index with
+// RemoveDocument'd documents doesn't resurrect them (see chunk0-2).
+// v3 adds the per-document language overrides from NewWithDocuments, so a
+// round trip doesn't silently fall back every document to auto-detection.
+const formatVersion = 3
+
+// Save writes the index to the file at path using Encode, creating or
+// truncating the file as needed.
+func (b *BM25S) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := b.Encode(w); err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	return w.Flush()
+}
+
+// Load reads an index previously written by Save from path.
+func Load(path string) (*BM25S, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return Decode(bufio.NewReader(f))
+}
+
+// Encode writes a packed, mmap-friendly representation of the index to w:
+// a small header followed by a sorted term -> postings list, so a
+// freshly-decoded instance can serve Search without re-tokenizing the
+// corpus. Term positions are not written (see Decode).
+func (b *BM25S) Encode(w io.Writer) error {
+	if err := writeUvarint(w, formatVersion); err != nil {
+		return err
+	}
+	if err := writeString(w, b.language); err != nil {
+		return err
+	}
+	if err := writeFloat64(w, b.k1); err != nil {
+		return err
+	}
+	if err := writeFloat64(w, b.b); err != nil {
+		return err
+	}
+	if err := writeBool(w, b.useIWF); err != nil {
+		return err
+	}
+	if err := writeFloat64(w, b.avgDocLength); err != nil {
+		return err
+	}
+
+	if err := writeUvarint(w, uint64(len(b.docs))); err != nil {
+		return err
+	}
+	for _, doc := range b.docs {
+		if err := writeString(w, doc); err != nil {
+			return err
+		}
+	}
+	for _, length := range b.docLengths {
+		if err := writeUvarint(w, uint64(length)); err != nil {
+			return err
+		}
+	}
+	for i := range b.docs {
+		tombstoned := i < len(b.tombstoned) && b.tombstoned[i]
+		if err := writeBool(w, tombstoned); err != nil {
+			return err
+		}
+	}
+
+	// docLanguages is only present for a NewWithDocuments corpus; a leading
+	// flag distinguishes "no per-document overrides" from "every document
+	// declared auto-detect".
+	hasDocLanguages := b.docLanguages != nil
+	if err := writeBool(w, hasDocLanguages); err != nil {
+		return err
+	}
+	if hasDocLanguages {
+		for _, lang := range b.docLanguages {
+			if err := writeString(w, lang); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Pack the postings as sorted term -> list of (docID, tf) pairs, with
+	// docIDs delta-encoded as varints.
+	// Tombstoned documents are skipped entirely so Decode doesn't
+	// resurrect their contribution to the postings or doc frequencies.
+	terms := make([]string, 0, len(b.docTermFreqs))
+	postings := make(map[string][]docPosting, len(b.termDocFreq))
+	for docIndex, tf := range b.docTermFreqs {
+		if docIndex < len(b.tombstoned) && b.tombstoned[docIndex] {
+			continue
+		}
+		for term, freq := range tf {
+			postings[term] = append(postings[term], docPosting{docIndex: docIndex, tf: freq})
+		}
+	}
+	for term := range postings {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	if err := writeUvarint(w, uint64(len(terms))); err != nil {
+		return err
+	}
+	for _, term := range terms {
+		if err := writeString(w, term); err != nil {
+			return err
+		}
+		list := postings[term]
+		sort.Slice(list, func(i, j int) bool { return list[i].docIndex < list[j].docIndex })
+
+		if err := writeUvarint(w, uint64(len(list))); err != nil {
+			return err
+		}
+		prevDoc := 0
+		for _, p := range list {
+			if err := writeUvarint(w, uint64(p.docIndex-prevDoc)); err != nil {
+				return err
+			}
+			if err := writeUvarint(w, uint64(p.tf)); err != nil {
+				return err
+			}
+			prevDoc = p.docIndex
+		}
+	}
+
+	return nil
+}
+
+// docPosting is a single (docID, tf) pair within a term's postings list.
+type docPosting struct {
+	docIndex int
+	tf       int
+}
+
+// Decode reconstructs a BM25S index previously written by Encode, without
+// re-tokenizing or re-stemming the corpus. Free-text and term queries score
+// as they did on the encoded instance. Term positions are not part of the
+// format, so phrase clauses match only documents added after Decode; the
+// tokenizer is reset to the default, WithWorkers is not restored, and k1/b
+// stay fixed instead of being re-tuned by AddDocument/RemoveDocument.
+func Decode(r io.Reader) (*BM25S, error) {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	version, err := readUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read format version: %w", err)
+	}
+	if version != formatVersion {
+		return nil, fmt.Errorf("unsupported bm25s format version %d", version)
+	}
+
+	language, err := readString(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read language: %w", err)
+	}
+	k1, err := readFloat64(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read k1: %w", err)
+	}
+	bParam, err := readFloat64(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read b: %w", err)
+	}
+	useIWF, err := readBool(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read useIWF: %w", err)
+	}
+	avgDocLength, err := readFloat64(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avgDocLength: %w", err)
+	}
+
+	docCount, err := readUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document count: %w", err)
+	}
+	docs := make([]string, docCount)
+	for i := range docs {
+		docs[i], err = readString(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document %d: %w", i, err)
+		}
+	}
+	docLengths := make([]int, docCount)
+	for i := range docLengths {
+		length, err := readUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document length %d: %w", i, err)
+		}
+		docLengths[i] = int(length)
+	}
+	tombstoned := make([]bool, docCount)
+	activeDocs := 0
+	for i := range tombstoned {
+		tombstoned[i], err = readBool(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tombstone flag %d: %w", i, err)
+		}
+		if !tombstoned[i] {
+			activeDocs++
+		}
+	}
+
+	hasDocLanguages, err := readBool(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docLanguages flag: %w", err)
+	}
+	var docLanguages []string
+	if hasDocLanguages {
+		docLanguages = make([]string, docCount)
+		for i := range docLanguages {
+			docLanguages[i], err = readString(br)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read docLanguages %d: %w", i, err)
+			}
+		}
+	}
+
+	b := &BM25S{
+		docs:          docs,
+		docLengths:    docLengths,
+		avgDocLength:  avgDocLength,
+		k1:            k1,
+		b:             bParam,
+		useIWF:        useIWF,
+		language:      language,
+		docLanguages:  docLanguages,
+		docTermFreqs:  make([]map[string]int, docCount),
+		tombstoned:    tombstoned,
+		activeDocs:    activeDocs,
+		termDocFreq:   make(map[string]int),
+		termTotalFreq: make(map[string]int),
+		postingsDirty: true,
+	}
+	b.tokenizer = b.tokenizeAndStem
+	// Term positions are not part of the packed format, so phrase queries
+	// find no adjacency hits in decoded documents (only in ones added later
+	// via AddDocument); each slot is initialized empty, not nil.
+	b.docTermPositions = make([]map[string][]int, docCount)
+	for i := range b.docTermFreqs {
+		b.docTermFreqs[i] = make(map[string]int)
+		b.docTermPositions[i] = make(map[string][]int)
+	}
+
+	termCount, err := readUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read term count: %w", err)
+	}
+	for i := uint64(0); i < termCount; i++ {
+		term, err := readString(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read term %d: %w", i, err)
+		}
+		listLen, err := readUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read postings length for %q: %w", term, err)
+		}
+
+		docIndex := 0
+		for j := uint64(0); j < listLen; j++ {
+			delta, err := readUvarint(br)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read posting delta for %q: %w", term, err)
+			}
+			tf, err := readUvarint(br)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read posting tf for %q: %w", term, err)
+			}
+			docIndex += int(delta)
+
+			b.docTermFreqs[docIndex][term] = int(tf)
+			b.termDocFreq[term]++
+			b.termTotalFreq[term] += int(tf)
+			b.totalTerms += int(tf)
+		}
+	}
+
+	return b, nil
+}
+
+func writeUvarint(w io.Writer, v uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func readUvarint(r byteReader) (uint64, error) {
+	return binary.ReadUvarint(r)
+}
+
+func writeString(w io.Writer, s string) error {
+	if err := writeUvarint(w, uint64(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+func readString(r byteReader) (string, error) {
+	n, err := readUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func writeFloat64(w io.Writer, f float64) error {
+	return writeUvarint(w, math.Float64bits(f))
+}
+
+func readFloat64(r byteReader) (float64, error) {
+	bits, err := readUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(bits), nil
+}
+
+func writeBool(w io.Writer, v bool) error {
+	var b byte
+	if v {
+		b = 1
+	}
+	_, err := w.Write([]byte{b})
+	return err
+}
+
+func readBool(r byteReader) (bool, error) {
+	buf := make([]byte, 1)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return false, err
+	}
+	return buf[0] != 0, nil
+}
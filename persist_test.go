@@ -0,0 +1,152 @@
+package bm25s
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+// TestEncodeDecodeRoundTrip verifies that Search results are identical
+// before and after a packed Encode/Decode round trip.
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	docs := []string{
+		"The quick brown fox jumps over the lazy dog",
+		"A fox fled from danger",
+		"Irrelevant document",
+	}
+	original := New(docs, WithLanguage("en"), WithIWF())
+
+	var buf bytes.Buffer
+	if err := original.Encode(&buf); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	wantResults := original.Search("fox", 2)
+	gotResults := decoded.Search("fox", 2)
+	if len(gotResults) != len(wantResults) {
+		t.Fatalf("Expected %d results, got %d", len(wantResults), len(gotResults))
+	}
+	for i := range wantResults {
+		if gotResults[i].DocIndex != wantResults[i].DocIndex {
+			t.Errorf("Result %d: expected DocIndex %d, got %d", i, wantResults[i].DocIndex, gotResults[i].DocIndex)
+		}
+		if gotResults[i].Score != wantResults[i].Score {
+			t.Errorf("Result %d: expected Score %f, got %f", i, wantResults[i].Score, gotResults[i].Score)
+		}
+	}
+}
+
+// TestSaveLoad verifies that Save/Load round-trips an index through disk.
+func TestSaveLoad(t *testing.T) {
+	docs := []string{
+		"Быстрая лисица перепрыгнула через собаку",
+		"Лисица убегала от опасности",
+	}
+	original := New(docs, WithLanguage("ru"))
+
+	path := filepath.Join(t.TempDir(), "index.bm25s")
+	if err := original.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if loaded.language != "ru" {
+		t.Errorf("Expected language 'ru', got %q", loaded.language)
+	}
+
+	results := loaded.Search("лисица", 2)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].DocIndex != 1 {
+		t.Errorf("Expected best match DocIndex 1, got %d", results[0].DocIndex)
+	}
+}
+
+// TestEncodeDecodeHonorsTombstones verifies that a RemoveDocument'd document
+// does not come back after a Save/Load round trip.
+func TestEncodeDecodeHonorsTombstones(t *testing.T) {
+	docs := []string{
+		"alpha document one",
+		"beta document two",
+		"gamma document three",
+	}
+	original := New(docs, WithLanguage("en"))
+	if err := original.RemoveDocument(1); err != nil {
+		t.Fatalf("RemoveDocument failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := original.Encode(&buf); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if decoded.activeDocs != 2 {
+		t.Errorf("Expected activeDocs 2, got %d", decoded.activeDocs)
+	}
+	for _, r := range decoded.Search("beta", 5) {
+		if r.DocIndex == 1 {
+			t.Errorf("Expected tombstoned document 1 to stay out of results, got %v", r)
+		}
+	}
+}
+
+// TestEncodeDecodePreservesDocLanguages verifies that the per-document
+// language overrides from NewWithDocuments survive an Encode/Decode round
+// trip instead of falling back to auto-detection.
+func TestEncodeDecodePreservesDocLanguages(t *testing.T) {
+	docs := []Document{
+		{Content: "Быстрая лисица убегала", Language: "ru"},
+		{Content: "The quick foxes were running", Language: "en"},
+	}
+	original := NewWithDocuments(docs)
+
+	var buf bytes.Buffer
+	if err := original.Encode(&buf); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if len(decoded.docLanguages) != 2 || decoded.docLanguages[0] != "ru" || decoded.docLanguages[1] != "en" {
+		t.Errorf("Expected docLanguages [ru en] to survive the round trip, got %v", decoded.docLanguages)
+	}
+
+	decoded.AddDocumentWithLanguage("Une lisiere rapide", "fr")
+	if err := decoded.RemoveDocument(0); err != nil {
+		t.Fatalf("RemoveDocument failed: %v", err)
+	}
+	remap := decoded.Compact()
+	if newIdx, ok := remap[2]; !ok || decoded.docLanguages[newIdx] != "fr" {
+		t.Errorf("Expected the added French document to survive Compact with its language intact, got %v", decoded.docLanguages)
+	}
+}
+
+// TestLoadUnsupportedVersion verifies that Decode rejects unknown format versions.
+func TestLoadUnsupportedVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeUvarint(&buf, formatVersion+1); err != nil {
+		t.Fatalf("writeUvarint failed: %v", err)
+	}
+
+	if _, err := Decode(&buf); err == nil {
+		t.Error("Expected an error for an unsupported format version, got nil")
+	}
+}
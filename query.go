@@ -0,0 +1,315 @@
+package bm25s
+
+import (
+	"strconv"
+	"strings"
+)
+
+// nodeKind identifies the kind of a parsed query AST node.
+type nodeKind int
+
+const (
+	nodeTerm nodeKind = iota
+	nodePhrase
+)
+
+// clause is a single parsed query clause: a Term or Phrase node, tagged
+// with how it participates in the overall query (Must/MustNot/Should).
+type clause struct {
+	kind  nodeKind
+	op    clauseOp
+	term  string   // stemmed term, set when kind == nodeTerm
+	words []string // stemmed phrase words, set when kind == nodePhrase
+	slop  int      // phrase slop from a "~N" suffix
+	field string   // optional "field:" prefix, reserved for future fielded indexes
+}
+
+// clauseOp is how a clause contributes to the overall query.
+type clauseOp int
+
+const (
+	// should contributes its score when present; it is never required.
+	should clauseOp = iota
+	// must requires the document to contain the clause with a non-zero
+	// contribution; it also contributes its score.
+	must
+	// mustNot prunes any document matching the clause before scoring.
+	mustNot
+)
+
+// queryAST is a parsed Search query: phrase and boolean operators over a
+// flat list of clauses.
+type queryAST struct {
+	clauses []clause
+	// hasPositive reports whether any clause is Must or Should, i.e. the
+	// query has a requirement that can produce a positive score. A query
+	// made up solely of MustNot clauses has none, and matches everything
+	// that isn't excluded.
+	hasPositive bool
+}
+
+// parseQuery parses raw into a queryAST. hasOperators reports whether raw
+// used any phrase/boolean syntax at all; when false the caller should fall
+// back to the legacy free-text scoring path so existing callers that never
+// use quotes, +/-, or field: prefixes see unchanged behavior.
+func (b *BM25S) parseQuery(raw string) (ast *queryAST, hasOperators bool) {
+	ast = &queryAST{}
+
+	for _, token := range splitQueryTokens(raw) {
+		op := should
+		switch {
+		case strings.HasPrefix(token, "+") && len(token) > 1:
+			op = must
+			token = token[1:]
+			hasOperators = true
+		case strings.HasPrefix(token, "-") && len(token) > 1:
+			op = mustNot
+			token = token[1:]
+			hasOperators = true
+		}
+
+		var field string
+		if name, rest, ok := splitField(token); ok {
+			field = name
+			token = rest
+			hasOperators = true
+		}
+
+		if phrase, slop, ok := splitPhrase(token); ok {
+			hasOperators = true
+			words := b.tokenizer(phrase)
+			if len(words) == 0 {
+				continue
+			}
+			ast.clauses = append(ast.clauses, clause{
+				kind: nodePhrase, op: op, words: words, slop: slop, field: field,
+			})
+			continue
+		}
+
+		for _, term := range b.tokenizer(token) {
+			ast.clauses = append(ast.clauses, clause{kind: nodeTerm, op: op, term: term, field: field})
+		}
+	}
+
+	for _, c := range ast.clauses {
+		if c.op != mustNot {
+			ast.hasPositive = true
+		}
+	}
+
+	return ast, hasOperators
+}
+
+// splitQueryTokens splits raw on space characters while keeping `"quoted phrases"`
+// (and a trailing "~N" slop suffix) together as a single token.
+func splitQueryTokens(raw string) []string {
+	var tokens []string
+	runes := []rune(raw)
+
+	for i := 0; i < len(runes); {
+		for i < len(runes) && runes[i] == ' ' {
+			i++
+		}
+		if i >= len(runes) {
+			break
+		}
+
+		start := i
+		if runes[i] == '"' || ((runes[i] == '+' || runes[i] == '-') && i+1 < len(runes) && runes[i+1] == '"') {
+			quoteStart := i
+			if runes[i] != '"' {
+				quoteStart = i + 1
+			}
+			end := quoteStart + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end < len(runes) {
+				end++ // include closing quote
+			}
+			// Consume an optional "~N" slop suffix right after the phrase.
+			for end < len(runes) && runes[end] != ' ' {
+				end++
+			}
+			i = end
+			tokens = append(tokens, string(runes[start:i]))
+			continue
+		}
+
+		for i < len(runes) && runes[i] != ' ' {
+			i++
+		}
+		tokens = append(tokens, string(runes[start:i]))
+	}
+
+	return tokens
+}
+
+// splitField extracts a leading "field:" prefix such as "title:fox". It
+// requires the field name to look like an identifier so query text
+// containing a bare colon (e.g. a timestamp) isn't misread as a field.
+func splitField(token string) (field, rest string, ok bool) {
+	idx := strings.IndexByte(token, ':')
+	if idx <= 0 || idx == len(token)-1 {
+		return "", "", false
+	}
+	name := token[:idx]
+	if name[0] >= '0' && name[0] <= '9' {
+		return "", "", false
+	}
+	for _, r := range name {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return "", "", false
+		}
+	}
+	return name, token[idx+1:], true
+}
+
+// splitPhrase extracts a `"quoted phrase"` and its optional `~N` slop
+// suffix from token.
+func splitPhrase(token string) (phrase string, slop int, ok bool) {
+	if len(token) < 2 || token[0] != '"' {
+		return "", 0, false
+	}
+
+	closing := strings.IndexByte(token[1:], '"')
+	if closing < 0 {
+		return "", 0, false
+	}
+	closing++ // index relative to token
+
+	phrase = token[1:closing]
+	rest := token[closing+1:]
+	if strings.HasPrefix(rest, "~") {
+		if n, err := strconv.Atoi(rest[1:]); err == nil {
+			slop = n
+		}
+	}
+
+	return phrase, slop, true
+}
+
+// matchesPhrase reports whether words occur, in order, within slop of each
+// other (slop 0 requires strict adjacency) in the document at docIndex.
+func (b *BM25S) matchesPhrase(docIndex int, words []string, slop int) bool {
+	if docIndex < 0 || docIndex >= len(b.docTermPositions) || len(words) == 0 {
+		return false
+	}
+
+	positions := b.docTermPositions[docIndex]
+	firstPositions, ok := positions[words[0]]
+	if !ok {
+		return false
+	}
+
+	for _, start := range firstPositions {
+		cur := start
+		matched := true
+		for _, word := range words[1:] {
+			next, found := nextPositionWithin(positions[word], cur, slop)
+			if !found {
+				matched = false
+				break
+			}
+			cur = next
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextPositionWithin returns the smallest position in candidates that is
+// after cur and no more than 1+slop tokens away, i.e. candidates for exact
+// adjacency (slop 0) must equal cur+1.
+func nextPositionWithin(candidates []int, cur, slop int) (int, bool) {
+	maxGap := 1 + slop
+	best := -1
+	for _, p := range candidates {
+		if p > cur && p-cur <= maxGap {
+			if best == -1 || p < best {
+				best = p
+			}
+		}
+	}
+	return best, best != -1
+}
+
+// phraseBoost scales the summed BM25 weight of a phrase's constituent
+// terms when the phrase actually occurs in the document.
+const phraseBoost = 1.5
+
+// scoreAST scores docIndex against a parsed query AST, applying Must/MustNot
+// pruning before accumulating Should/Must contributions.
+func (b *BM25S) scoreAST(docIndex int, ast *queryAST) (float64, bool) {
+	score := 0.0
+
+	for _, c := range ast.clauses {
+		contribution, matched := b.scoreClause(docIndex, c)
+
+		switch c.op {
+		case mustNot:
+			if matched {
+				return 0, false
+			}
+		case must:
+			if !matched {
+				return 0, false
+			}
+			score += contribution
+		default: // should
+			score += contribution
+		}
+	}
+
+	return score, true
+}
+
+// scoreClause scores a single clause against a document, reporting whether
+// it matched (non-zero term score, or a phrase actually found in order).
+func (b *BM25S) scoreClause(docIndex int, c clause) (float64, bool) {
+	switch c.kind {
+	case nodePhrase:
+		if !b.matchesPhrase(docIndex, c.words, c.slop) {
+			return 0, false
+		}
+		return b.scoreTerms(docIndex, c.words) * phraseBoost, true
+	default:
+		score := b.scoreTerms(docIndex, []string{c.term})
+		return score, score > 0
+	}
+}
+
+// searchAST evaluates a parsed query across all active documents and
+// returns the top-N results ranked by score, mirroring Search's ranking.
+func (b *BM25S) searchAST(ast *queryAST, topN int) []SearchResult {
+	results := make([]SearchResult, 0, len(b.docs))
+
+	for i := range b.docs {
+		if i < len(b.tombstoned) && b.tombstoned[i] {
+			continue
+		}
+		score, ok := b.scoreAST(i, ast)
+		if !ok {
+			continue
+		}
+		// A query with no Must/Should clause (pure exclusion) has nothing
+		// to produce a positive score, so surviving MustNot pruning is
+		// itself a match instead of requiring score > 0.
+		if ast.hasPositive && score <= 0 {
+			continue
+		}
+		results = append(results, SearchResult{DocIndex: i, Score: score, Doc: b.docs[i]})
+	}
+
+	sortResultsByScore(results)
+
+	if topN > 0 && len(results) > topN {
+		results = results[:topN]
+	}
+
+	return results
+}
@@ -0,0 +1,100 @@
+package bm25s
+
+import "testing"
+
+// TestSearchPlainQueryUnaffected verifies that a query with no operators
+// keeps scoring identically to the legacy bag-of-words path.
+func TestSearchPlainQueryUnaffected(t *testing.T) {
+	docs := []string{
+		"The quick brown fox jumps over the lazy dog",
+		"A fox fled from danger",
+		"Irrelevant document",
+	}
+	bm25 := New(docs)
+
+	results := bm25.Search("fox", 2)
+	if len(results) != 2 || results[0].DocIndex != 1 || results[1].DocIndex != 0 {
+		t.Errorf("Expected plain query ranking [1, 0], got %v", results)
+	}
+}
+
+// TestSearchPhraseQuery verifies that a quoted phrase only matches
+// documents where the words occur adjacently, in order.
+func TestSearchPhraseQuery(t *testing.T) {
+	docs := []string{
+		"the quick brown fox jumps",
+		"the fox is quick and brown",
+	}
+	bm25 := New(docs)
+
+	results := bm25.Search(`"quick brown fox"`, 2)
+	if len(results) != 1 || results[0].DocIndex != 0 {
+		t.Fatalf("Expected only docIndex 0 to match phrase \"quick brown fox\", got %v", results)
+	}
+}
+
+// TestSearchPhraseSlop verifies that a "~N" slop suffix allows the phrase
+// words to match with up to N intervening tokens.
+func TestSearchPhraseSlop(t *testing.T) {
+	docs := []string{"the quick brown lazy fox jumps"}
+	bm25 := New(docs)
+
+	if results := bm25.Search(`"quick fox"`, 1); len(results) != 0 {
+		t.Errorf("Expected no exact-adjacency match for \"quick fox\", got %v", results)
+	}
+
+	results := bm25.Search(`"quick fox"~2`, 1)
+	if len(results) != 1 {
+		t.Errorf("Expected \"quick fox\"~2 to match with slop, got %v", results)
+	}
+}
+
+// TestSearchMustAndMustNot verifies +required and -excluded term handling.
+func TestSearchMustAndMustNot(t *testing.T) {
+	docs := []string{
+		"apples and oranges",
+		"apples only",
+		"oranges only",
+	}
+	bm25 := New(docs)
+
+	results := bm25.Search("+apples -oranges", 3)
+	if len(results) != 1 || results[0].DocIndex != 1 {
+		t.Errorf("Expected only docIndex 1 to satisfy +apples -oranges, got %v", results)
+	}
+}
+
+// TestSearchPureMustNot verifies that a query made up solely of MustNot
+// clauses returns every non-excluded document instead of nothing, since it
+// has no Must/Should clause to produce a positive score.
+func TestSearchPureMustNot(t *testing.T) {
+	docs := []string{
+		"apples and oranges",
+		"apples only",
+		"plain text",
+		"more plain text",
+	}
+	bm25 := New(docs)
+
+	results := bm25.Search("-oranges", 10)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 non-excluded documents for -oranges, got %d: %v", len(results), results)
+	}
+	for _, r := range results {
+		if r.DocIndex == 0 {
+			t.Errorf("Expected docIndex 0 to be excluded by -oranges, got %v", results)
+		}
+	}
+}
+
+// TestSearchFieldPrefix verifies that a field:term prefix is parsed without
+// erroring and still matches on the term.
+func TestSearchFieldPrefix(t *testing.T) {
+	docs := []string{"title contains fox content", "unrelated content"}
+	bm25 := New(docs)
+
+	results := bm25.Search("title:fox", 2)
+	if len(results) != 1 || results[0].DocIndex != 0 {
+		t.Errorf("Expected field-prefixed query to match docIndex 0, got %v", results)
+	}
+}
@@ -0,0 +1,211 @@
+package rag
+
+import "unicode"
+
+// FuzzyAlgo selects the fuzzy matching algorithm used by DocMatch.
+type FuzzyAlgo int
+
+const (
+	// AlgoV1 preserves the original Levenshtein-distance based matching.
+	AlgoV1 FuzzyAlgo = iota
+	// AlgoV2 uses a positional fzf-v2-style scorer: it locates the leftmost
+	// in-order match of the query characters and runs a dynamic program
+	// over that window rewarding consecutive runs, word boundaries, and
+	// camelCase transitions. It is the default algorithm.
+	AlgoV2
+)
+
+// Scoring constants loosely modeled on fzf's v2 algorithm.
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	bonusBoundary    = scoreMatch / 2
+	bonusCamelCase   = bonusBoundary + 1
+	bonusConsecutive = scoreMatch / 2
+	bonusFirstChar   = 2 // multiplier applied to the first query character's bonus
+)
+
+// isBoundaryRune reports whether r is a separator after which a match
+// should be rewarded as starting a new "word".
+func isBoundaryRune(r rune) bool {
+	switch r {
+	case '/', '_', '-', '.', ' ':
+		return true
+	}
+	return false
+}
+
+// classOf classifies r for camelCase-transition and word-exactness scoring.
+type charClass int
+
+const (
+	classNone charClass = iota
+	classLower
+	classUpper
+	classDigit
+)
+
+func classOf(r rune) charClass {
+	switch {
+	case unicode.IsLower(r):
+		return classLower
+	case unicode.IsUpper(r):
+		return classUpper
+	case unicode.IsDigit(r):
+		return classDigit
+	}
+	return classNone
+}
+
+// bonusAt returns the positional bonus for matching candidate[j], given the
+// previous candidate rune prev (or 0 at the start of the string).
+func bonusAt(prev, cur rune) int {
+	if prev == 0 || isBoundaryRune(prev) {
+		return bonusBoundary
+	}
+	if classOf(prev) == classLower && classOf(cur) == classUpper {
+		return bonusCamelCase
+	}
+	if classOf(prev) == classNone && classOf(cur) != classNone {
+		return bonusBoundary
+	}
+	return 0
+}
+
+// fuzzyScoreV2 implements a positional fzf-v2-style scorer. It returns the
+// normalized score (roughly 0.0-1.0, but not hard-capped) and whether query
+// matches candidate at all as a subsequence.
+func fuzzyScoreV2(query, candidate string) (float64, bool) {
+	if len(query) == 0 {
+		return 1.0, true
+	}
+
+	q := []rune(toLower(query))
+	c := []rune(candidate)
+	cLower := []rune(toLower(candidate))
+
+	// Find the leftmost in-order match to bound the scoring window, as fzf
+	// does before running its DP over the (typically much smaller) window.
+	start := -1
+	end := -1
+	qi := 0
+	for j := range cLower {
+		if qi < len(q) && cLower[j] == q[qi] {
+			if start == -1 {
+				start = j
+			}
+			qi++
+			if qi == len(q) {
+				end = j
+				break
+			}
+		}
+	}
+	if end == -1 {
+		return 0, false
+	}
+
+	window := c[start : end+1]
+	windowLower := cLower[start : end+1]
+	n := len(window)
+	m := len(q)
+
+	// H[i][j]: best score matching q[0:i+1] ending with q[i] at window[j].
+	// C[i][j]: length of the consecutive match streak ending at that cell.
+	h := make([][]int, m)
+	streak := make([][]int, m)
+	for i := range h {
+		h[i] = make([]int, n)
+		streak[i] = make([]int, n)
+	}
+
+	const negInf = -1 << 30
+
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			h[i][j] = negInf
+			if windowLower[j] != q[i] {
+				continue
+			}
+
+			var prevRune rune
+			if start+j > 0 {
+				prevRune = c[start+j-1]
+			}
+			bonus := bonusAt(prevRune, window[j])
+
+			if i == 0 {
+				// Matching the first query rune starts a fresh run.
+				h[i][j] = scoreMatch + bonus*bonusFirstChar
+				streak[i][j] = 1
+				continue
+			}
+
+			best := negInf
+			bestStreak := 0
+
+			// Extend a consecutive run from the immediately preceding cell.
+			if j > 0 && h[i-1][j-1] != negInf {
+				consecutive := streak[i-1][j-1] + 1
+				consecutiveBonus := bonus
+				if consecutiveBonus < bonusConsecutive {
+					consecutiveBonus = bonusConsecutive
+				}
+				score := h[i-1][j-1] + scoreMatch + consecutiveBonus
+				if score > best {
+					best = score
+					bestStreak = consecutive
+				}
+			}
+
+			// Skip a gap of candidate runes since the previous matched query rune.
+			for k := 0; k < j; k++ {
+				if h[i-1][k] == negInf {
+					continue
+				}
+				gap := j - k - 1
+				penalty := 0
+				if gap > 0 {
+					penalty = scoreGapStart + (gap-1)*scoreGapExtension
+				}
+				score := h[i-1][k] + scoreMatch + bonus + penalty
+				if score > best {
+					best = score
+					bestStreak = 1
+				}
+			}
+
+			h[i][j] = best
+			streak[i][j] = bestStreak
+		}
+	}
+
+	best := negInf
+	for j := 0; j < n; j++ {
+		if h[m-1][j] > best {
+			best = h[m-1][j]
+		}
+	}
+	if best == negInf {
+		return 0, false
+	}
+
+	// Whole-word exact match bonus: the entire candidate equals the query.
+	if string(cLower) == string(q) {
+		best += scoreMatch * m
+	}
+
+	maxPossible := float64((scoreMatch + bonusCamelCase*bonusFirstChar) * m)
+	normalized := float64(best) / maxPossible
+	return normalized, true
+}
+
+func toLower(s string) string {
+	r := []rune(s)
+	for i, c := range r {
+		r[i] = unicode.ToLower(c)
+	}
+	return string(r)
+}
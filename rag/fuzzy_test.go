@@ -0,0 +1,74 @@
+package rag
+
+import "testing"
+
+// TestFuzzyScoreV2Match verifies that an in-order subsequence match scores
+// higher when it forms a consecutive run than when it is scattered.
+func TestFuzzyScoreV2Match(t *testing.T) {
+	consecutive, ok := fuzzyScoreV2("fox", "a quick fox jumps")
+	if !ok {
+		t.Fatal("Expected a match for 'fox' in 'a quick fox jumps'")
+	}
+
+	scattered, ok := fuzzyScoreV2("fox", "f.o.x scattered far apart")
+	if !ok {
+		t.Fatal("Expected a match for 'fox' in 'f.o.x scattered far apart'")
+	}
+
+	if consecutive <= scattered {
+		t.Errorf("Expected consecutive match score (%f) to exceed scattered match score (%f)", consecutive, scattered)
+	}
+}
+
+// TestFuzzyScoreV2NoMatch verifies that a query whose characters are not a
+// subsequence of the candidate is reported as no match.
+func TestFuzzyScoreV2NoMatch(t *testing.T) {
+	if _, ok := fuzzyScoreV2("xyz", "abc def"); ok {
+		t.Error("Expected no match for 'xyz' in 'abc def'")
+	}
+}
+
+// TestFindFuzzyMatchesV2DefaultThreshold verifies that NewRAGLLM's default
+// threshold accepts ordinary contiguous substring matches under AlgoV2's DP
+// score scale, rather than the higher threshold tuned for AlgoV1's 0-1
+// Levenshtein similarity ratio.
+func TestFindFuzzyMatchesV2DefaultThreshold(t *testing.T) {
+	docs := []ResponseEntry{
+		{ID: "1", Content: "Please review the complete refund and policy details in section 4"},
+	}
+	dm := &DocMatch{documents: docs, ftr: 0.6, algo: AlgoV2}
+	dm.preprocessDocuments()
+
+	matches := dm.findFuzzyMatches("refund policy")
+	if len(matches) != 1 || matches[0].ID != "1" {
+		t.Errorf("Expected default AlgoV2 threshold to match entry '1', got %v", matches)
+	}
+}
+
+// TestFindFuzzyMatchesAlgoSelection verifies that WithFuzzyAlgo switches
+// between the V1 (Levenshtein) and V2 (fzf-style) matchers.
+func TestFindFuzzyMatchesAlgoSelection(t *testing.T) {
+	docs := []ResponseEntry{
+		{ID: "1", Content: "fox"},
+		{ID: "2", Content: "completely unrelated text"},
+	}
+
+	dm := &DocMatch{documents: docs, ftr: 0.01, algo: AlgoV2}
+	dm.preprocessDocuments()
+
+	matches := dm.findFuzzyMatches("fox")
+	if len(matches) != 1 || matches[0].ID != "1" {
+		t.Errorf("Expected AlgoV2 to match only entry '1' for exact query 'fox', got %v", matches)
+	}
+
+	// AlgoV1's Levenshtein similarity for "fox" against the unrelated entry
+	// is still slightly above zero, so it needs its own, much higher
+	// threshold than AlgoV2's "ok" cutoff to discriminate between the two
+	// entries here.
+	dm.algo = AlgoV1
+	dm.ftr = 0.5
+	matches = dm.findFuzzyMatches("fox")
+	if len(matches) != 1 || matches[0].ID != "1" {
+		t.Errorf("Expected AlgoV1 to match only entry '1' for exact query 'fox', got %v", matches)
+	}
+}
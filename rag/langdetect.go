@@ -0,0 +1,110 @@
+package rag
+
+import (
+	"math"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// detectionConfidenceThreshold is the minimum cosine similarity against the
+// closest trigram profile before ProcessQuery trusts DetectLanguage's match
+// instead of falling back to the configured default language.
+const detectionConfidenceThreshold = 0.15
+
+// langProfile holds a normalized character-trigram frequency table built
+// from a small bundled sample of text in one language.
+type langProfile struct {
+	tag      language.Tag
+	trigrams map[string]float64
+}
+
+// languageProfiles is the bundled training corpus: one short representative
+// sample per supported language, enough to separate scripts and the most
+// common trigrams of each language without shipping a large dataset.
+var languageProfiles = buildLanguageProfiles(map[language.Tag]string{
+	language.English: "the quick brown fox jumps over the lazy dog while the cat watches quietly from the window",
+	language.Russian: "быстрая лисица перепрыгнула через ленивую собаку пока кошка тихо наблюдала из окна",
+	language.French:  "le rapide renard brun saute par dessus le chien paresseux pendant que le chat regarde tranquillement",
+	language.German:  "der schnelle braune fuchs springt über den faulen hund während die katze ruhig aus dem fenster schaut",
+	language.Spanish: "el rápido zorro marrón salta sobre el perro perezoso mientras el gato observa tranquilamente desde la ventana",
+	language.Chinese: "敏捷的棕色狐狸跳过了懒惰的狗而猫正安静地从窗户里看着",
+})
+
+func buildLanguageProfiles(corpus map[language.Tag]string) []langProfile {
+	profiles := make([]langProfile, 0, len(corpus))
+	for tag, sample := range corpus {
+		profiles = append(profiles, langProfile{tag: tag, trigrams: trigramFrequencies(sample)})
+	}
+	return profiles
+}
+
+// trigramFrequencies tokenizes text into overlapping rune trigrams (padded
+// with boundary spaces so short words still contribute a trigram) and
+// returns each trigram's normalized frequency.
+func trigramFrequencies(text string) map[string]float64 {
+	runes := []rune(" " + strings.ToLower(strings.Join(strings.Fields(text), " ")) + " ")
+	counts := make(map[string]float64)
+
+	for i := 0; i+3 <= len(runes); i++ {
+		counts[string(runes[i:i+3])]++
+	}
+
+	total := 0.0
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return counts
+	}
+	for t := range counts {
+		counts[t] /= total
+	}
+	return counts
+}
+
+// cosineSimilarity compares two trigram frequency tables: the dot product
+// over their shared keys divided by the L2 norms of both tables.
+func cosineSimilarity(a, b map[string]float64) float64 {
+	small, large := a, b
+	if len(b) < len(a) {
+		small, large = b, a
+	}
+
+	var dot, normA, normB float64
+	for t, freq := range small {
+		if other, ok := large[t]; ok {
+			dot += freq * other
+		}
+	}
+	for _, freq := range a {
+		normA += freq * freq
+	}
+	for _, freq := range b {
+		normB += freq * freq
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// DetectLanguage classifies text against the bundled per-language trigram
+// profiles and returns the closest BCP-47 tag along with a 0.0-1.0
+// confidence (cosine similarity to that profile). Callers should fall back
+// to their own default when confidence is low.
+func DetectLanguage(text string) (language.Tag, float64) {
+	query := trigramFrequencies(text)
+
+	bestTag := language.Und
+	bestScore := 0.0
+	for _, profile := range languageProfiles {
+		score := cosineSimilarity(query, profile.trigrams)
+		if score > bestScore {
+			bestScore = score
+			bestTag = profile.tag
+		}
+	}
+
+	return bestTag, bestScore
+}
@@ -0,0 +1,39 @@
+package rag
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+// TestDetectLanguage verifies that DetectLanguage distinguishes a few
+// scripts/languages using the bundled trigram profiles.
+func TestDetectLanguage(t *testing.T) {
+	cases := []struct {
+		text string
+		want language.Tag
+	}{
+		{"the quick brown fox jumps over the lazy dog", language.English},
+		{"быстрая лисица перепрыгнула через ленивую собаку", language.Russian},
+		{"le chat regarde tranquillement par la fenêtre", language.French},
+	}
+
+	for _, tc := range cases {
+		got, confidence := DetectLanguage(tc.text)
+		if got != tc.want {
+			t.Errorf("DetectLanguage(%q) = %v (confidence %f), want %v", tc.text, got, confidence, tc.want)
+		}
+		if confidence <= 0 {
+			t.Errorf("DetectLanguage(%q) returned non-positive confidence %f", tc.text, confidence)
+		}
+	}
+}
+
+// TestDetectLanguageLowConfidenceForEmpty verifies that an empty or
+// unrecognizable string yields low confidence so callers fall back.
+func TestDetectLanguageLowConfidenceForEmpty(t *testing.T) {
+	_, confidence := DetectLanguage("")
+	if confidence >= detectionConfidenceThreshold {
+		t.Errorf("Expected low confidence for empty text, got %f", confidence)
+	}
+}
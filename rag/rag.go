@@ -12,6 +12,7 @@ import (
 	"github.com/agnivade/levenshtein"
 	"github.com/covrom/bm25s"
 	"github.com/sashabaranov/go-openai"
+	"golang.org/x/text/language"
 )
 
 // ResponseEntry represents an entry in the pre-prepared response database
@@ -26,12 +27,43 @@ type DocMatch struct {
 	documents []ResponseEntry // Collection of response entries
 	bm        *bm25s.BM25S    // BM25S instance for document search
 	ftr       float64         // Fuzzy matching threshold (0.0 to 1.0)
+	algo      FuzzyAlgo       // Fuzzy matching algorithm
 }
 
 // RAGLLM handles the processing of user queries with BM25 and LLM integration
 type RAGLLM struct {
 	dm           *DocMatch      // Document matcher instance
 	openaiClient *openai.Client // OpenAI API client
+	defaultLang  language.Tag   // Fallback language when DetectLanguage is unconfident
+}
+
+// Option configures a RAGLLM instance
+type Option func(*RAGLLM)
+
+// WithFuzzyThreshold sets the similarity threshold (0.0 to 1.0) above which
+// findFuzzyMatches considers a document a match.
+func WithFuzzyThreshold(threshold float64) Option {
+	return func(p *RAGLLM) {
+		p.dm.ftr = threshold
+	}
+}
+
+// WithFuzzyAlgo selects the fuzzy matching algorithm: AlgoV1 preserves the
+// original Levenshtein-distance behavior, AlgoV2 (the default) uses the
+// fzf-v2-style positional scorer.
+func WithFuzzyAlgo(algo FuzzyAlgo) Option {
+	return func(p *RAGLLM) {
+		p.dm.algo = algo
+	}
+}
+
+// WithDefaultLanguage sets the language ProcessQuery falls back to when
+// DetectLanguage cannot classify the query with enough confidence. Defaults
+// to language.English.
+func WithDefaultLanguage(tag language.Tag) Option {
+	return func(p *RAGLLM) {
+		p.defaultLang = tag
+	}
 }
 
 // NewRAGLLM initializes the processor with response database and OpenAI client
@@ -39,10 +71,12 @@ type RAGLLM struct {
 //   - responseDB: collection of pre-prepared responses
 //   - apiBaseURL: base URL for OpenAI API
 //   - openaiAPIKey: authentication key for OpenAI API
-func NewRAGLLM(responseDB []ResponseEntry, apiBaseURL, openaiAPIKey string) (*RAGLLM, error) {
+//   - opts: optional configuration, e.g. WithFuzzyThreshold, WithFuzzyAlgo
+func NewRAGLLM(responseDB []ResponseEntry, apiBaseURL, openaiAPIKey string, opts ...Option) (*RAGLLM, error) {
 	bm25 := &DocMatch{
 		documents: responseDB,
-		ftr:       0.75, // Default fuzzy matching threshold (75% similarity)
+		ftr:       0.6, // Default fuzzy matching threshold, calibrated for AlgoV2's DP score scale
+		algo:      AlgoV2,
 	}
 
 	bm25.preprocessDocuments()
@@ -51,10 +85,17 @@ func NewRAGLLM(responseDB []ResponseEntry, apiBaseURL, openaiAPIKey string) (*RA
 	config.BaseURL = apiBaseURL // Can be set to "https://api.openai.com/v1" or custom endpoint
 
 	client := openai.NewClientWithConfig(config)
-	return &RAGLLM{
+	p := &RAGLLM{
 		dm:           bm25,
 		openaiClient: client,
-	}, nil
+		defaultLang:  language.English,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
 }
 
 // preprocessDocuments tokenizes and stems documents for BM25 search
@@ -69,6 +110,15 @@ func (dm *DocMatch) preprocessDocuments() {
 // findFuzzyMatches performs fuzzy search for the query against document contents.
 // It returns a slice of ResponseEntry that are above the similarity threshold.
 func (dm *DocMatch) findFuzzyMatches(query string) []ResponseEntry {
+	if dm.algo == AlgoV1 {
+		return dm.findFuzzyMatchesLevenshtein(query)
+	}
+	return dm.findFuzzyMatchesV2(query)
+}
+
+// findFuzzyMatchesLevenshtein is the original full Levenshtein-distance
+// based matcher, preserved under AlgoV1 for backward compatibility.
+func (dm *DocMatch) findFuzzyMatchesLevenshtein(query string) []ResponseEntry {
 	threshold := dm.ftr
 	var matches []ResponseEntry
 
@@ -97,6 +147,21 @@ func (dm *DocMatch) findFuzzyMatches(query string) []ResponseEntry {
 	return matches
 }
 
+// findFuzzyMatchesV2 ranks documents with the fzf-v2-style positional
+// scorer, which favors leftmost in-order matches with consecutive runs and
+// word-boundary/camelCase hits over raw edit distance.
+func (dm *DocMatch) findFuzzyMatchesV2(query string) []ResponseEntry {
+	var matches []ResponseEntry
+
+	for _, entry := range dm.documents {
+		score, ok := fuzzyScoreV2(query, entry.Content)
+		if ok && score >= dm.ftr {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
 // readFileContent reads a file and ensures it's valid UTF-8 encoded
 func readFileContent(filePath string) (string, error) {
 	data, err := os.ReadFile(filePath)
@@ -138,7 +203,7 @@ func (p *RAGLLM) ProcessQuery(ctx context.Context, model, sysprompt, query strin
 	// --- Fuzzy Search Retrieval ---
 	var fuzzyMatches []ResponseEntry
 	if useFuzzy {
-		// Find documents with similarity above threshold (0.75 by default)
+		// Find documents with similarity above threshold (0.6 by default)
 		fuzzyMatches = p.dm.findFuzzyMatches(query)
 	}
 
@@ -200,24 +265,15 @@ func (p *RAGLLM) ProcessQuery(ctx context.Context, model, sysprompt, query strin
 		fmt.Fprintf(sb, "Content of file %s:\n%s\n", filepath.Base(filePath), content)
 	}
 
-	// Detect language based on character counts
-	var cyrCount, latCount int
-	for _, r := range query {
-		switch {
-		case r >= 'а' && r <= 'я' || r >= 'А' && r <= 'Я':
-			cyrCount++
-		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
-			latCount++
-		}
-	}
-
-	lang := "english"
-	if cyrCount > latCount {
-		lang = "russian"
+	// Detect the query language via trigram classification, falling back to
+	// the configured default when the classifier isn't confident.
+	lang, confidence := DetectLanguage(query)
+	if confidence < detectionConfidenceThreshold {
+		lang = p.defaultLang
 	}
 
 	// Add the main query prompt
-	fmt.Fprintf(sb, "Answer the question in %s language:\n%s\n", lang, query)
+	fmt.Fprintf(sb, "Answer the question in %s language:\n%s\n", lang.String(), query)
 
 	messages = append(messages, openai.ChatCompletionMessage{
 		Role:    openai.ChatMessageRoleUser,